@@ -0,0 +1,38 @@
+package firehose
+
+import (
+	"log"
+	"time"
+)
+
+// AuditRecord is a single structured record of an auth decision, covering
+// both successes and failures so operators can reconstruct who accessed
+// what.
+type AuditRecord struct {
+	Timestamp  time.Time
+	RemoteAddr string
+	Scheme     string
+	Label      string
+	Path       string
+	Outcome    string // "allow" or "deny"
+	Reason     string
+}
+
+// AuditLogger is invoked by AuthHandler on every auth decision, success and
+// failure, so operators can ship the records to SIEM tools.
+type AuditLogger interface {
+	Audit(record AuditRecord)
+}
+
+// LogAuditLogger is the default AuditLogger: it writes each record through
+// the standard log package, replacing the package's original bare
+// log.Println("Unauthorised:", reason) call with a structured line.
+type LogAuditLogger struct{}
+
+// Audit implements AuditLogger.
+func (LogAuditLogger) Audit(record AuditRecord) {
+	log.Printf(
+		"firehose auth: outcome=%s scheme=%q label=%q remote=%q path=%q reason=%q",
+		record.Outcome, record.Scheme, record.Label, record.RemoteAddr, record.Path, record.Reason,
+	)
+}
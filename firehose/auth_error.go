@@ -0,0 +1,36 @@
+package firehose
+
+// AuthErrorKind classifies why an authentication attempt failed, so
+// middleware users can log or meter them separately and so AuthHandler can
+// pick the right WWW-Authenticate challenge.
+type AuthErrorKind int
+
+const (
+	// ErrMissingCredentials means the request carried no credentials for
+	// this scheme at all.
+	ErrMissingCredentials AuthErrorKind = iota
+	// ErrMalformedCredentials means the credentials could not be parsed
+	// (bad base64, wrong shape, ...).
+	ErrMalformedCredentials
+	// ErrInvalidCredentials means the credentials parsed fine but did not
+	// match any known principal, or had expired.
+	ErrInvalidCredentials
+)
+
+// AuthError is returned by Authenticators to describe a failed attempt at
+// their scheme, distinguishing malformed vs. invalid vs. missing
+// credentials.
+type AuthError struct {
+	Kind AuthErrorKind
+	// Scheme is the authentication scheme the request attempted, e.g.
+	// "Bearer" or "Basic", used to pick a WWW-Authenticate challenge.
+	Scheme string
+	// Reason is a human readable description suitable for logging and for
+	// the response body.
+	Reason string
+}
+
+// Error implements the error interface.
+func (e *AuthError) Error() string {
+	return e.Reason
+}
@@ -1,80 +1,164 @@
 package firehose
 
 import (
+	"context"
 	"encoding/base64"
 	"errors"
-	"log"
+	"fmt"
 	"net/http"
 	"strings"
+	"time"
+	"unicode/utf8"
 )
 
-// This HTTP middleware can wrap any http.Handler to add token-based authentication.
-// It will attempt to find a token in
-//   1. A "bearer" token in the Authentication header
-//   2. Basic Authentication
-//   3. An "access_token" parameter in the query string (usable in browser's EventSource API)
+// This HTTP middleware can wrap any http.Handler to add authentication and
+// authorization. It runs a configurable pipeline of Authenticators, trying
+// each in order until one succeeds, and then a configurable pipeline of
+// Authorizers, all of which must pass, modeled on the bascule approach of
+// combining schemes (bearer, basic, query token, ...) with layered
+// authorization rules (role, IP allowlist, path prefix, method, ...).
+
+// defaultRealm is used when NewAuthHandler is not given a WithRealm option.
+const defaultRealm = "firehose"
 
 // The middleware object and basic state
 type AuthHandler struct {
 	// instance of http.Handler to be decorated
 	app http.Handler
-	// The access token
-	token string
+	// tried in order; the first Authenticator to return ok == true wins
+	authenticators []Authenticator
+	// all of these must pass for an authenticated request to proceed
+	authorizers []Authorizer
+	// realm advertised in WWW-Authenticate challenges
+	realm string
+	// audit is invoked on every auth decision, success and failure
+	audit AuditLogger
 }
 
 // Implement the http.Handler interface.
 // http://golang.org/pkg/net/http/#Handler
 func (handler *AuthHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 
-	unauthorized, reason := handler.authorize(req)
-
-	if unauthorized == true {
-		log.Println("Unauthorised:", reason)
-		http.Error(rw, reason, http.StatusUnauthorized)
+	principal, authErr := handler.authenticate(req)
+
+	if principal == nil {
+		handler.challenge(rw, authErr)
+		handler.audit.Audit(AuditRecord{
+			Timestamp:  time.Now(),
+			RemoteAddr: req.RemoteAddr,
+			Scheme:     authErr.Scheme,
+			Path:       req.URL.Path,
+			Outcome:    "deny",
+			Reason:     authErr.Reason,
+		})
+		http.Error(rw, authErr.Reason, http.StatusUnauthorized)
 		return
 	}
 
-	handler.app.ServeHTTP(rw, req)
-}
+	for _, authorizer := range handler.authorizers {
+		if ok, reason := authorizer.Authorize(principal, req); !ok {
+			handler.audit.Audit(AuditRecord{
+				Timestamp:  time.Now(),
+				RemoteAddr: req.RemoteAddr,
+				Scheme:     principal.Scheme,
+				Label:      principal.Label,
+				Path:       req.URL.Path,
+				Outcome:    "deny",
+				Reason:     reason,
+			})
+			http.Error(rw, reason, http.StatusForbidden)
+			return
+		}
+	}
 
-// Detect auth scheme and deal with it accordingly
-func (handler *AuthHandler) authorize(req *http.Request) (unauthorized bool, reason string) {
-	scheme, credentials, _ := ParseRequest(req)
+	handler.audit.Audit(AuditRecord{
+		Timestamp:  time.Now(),
+		RemoteAddr: req.RemoteAddr,
+		Scheme:     principal.Scheme,
+		Label:      principal.Label,
+		Path:       req.URL.Path,
+		Outcome:    "allow",
+	})
+
+	ctx := context.WithValue(req.Context(), principalContextKey, principal)
+	handler.app.ServeHTTP(rw, req.WithContext(ctx))
+}
 
-	switch scheme {
-	case "Bearer":
-		if credentials != handler.token {
-			unauthorized = true
-			reason = "Invalid Access Token"
+// authenticate runs handler.authenticators in order and returns the
+// Principal of the first one that succeeds. If none succeed it returns a
+// nil Principal and the error of the last Authenticator that reported one,
+// or an ErrMissingCredentials AuthError if none did.
+func (handler *AuthHandler) authenticate(req *http.Request) (principal *Principal, authErr *AuthError) {
+	for _, authenticator := range handler.authenticators {
+		principal, ok, err := authenticator.Authenticate(req)
+		if ok {
+			return principal, nil
 		}
-	case "Basic":
-		basic, err := NewBasic(credentials)
 		if err != nil {
-			unauthorized = true
-			reason = "Malformed Basic Authorization crdentials"
-		}
-		if basic.Password != handler.token {
-			unauthorized = true
-			reason = "Invalid credentials"
+			if ae, ok := err.(*AuthError); ok {
+				authErr = ae
+			} else {
+				authErr = &AuthError{Kind: ErrInvalidCredentials, Reason: err.Error()}
+			}
 		}
+	}
+
+	if authErr == nil {
+		authErr = &AuthError{Kind: ErrMissingCredentials, Reason: "Missing or invalid credentials"}
+	}
+	return nil, authErr
+}
+
+// challenge sets the WWW-Authenticate header appropriate for authErr's
+// scheme so standard HTTP clients and browsers can negotiate credentials.
+func (handler *AuthHandler) challenge(rw http.ResponseWriter, authErr *AuthError) {
+	errorCode := "invalid_token"
+	if authErr.Kind == ErrMissingCredentials || authErr.Kind == ErrMalformedCredentials {
+		errorCode = "invalid_request"
+	}
+
+	switch authErr.Scheme {
+	case "Basic":
+		rw.Header().Set("WWW-Authenticate", fmt.Sprintf(`Basic realm=%q, charset="UTF-8"`, handler.realm))
+	case HMACScheme:
+		rw.Header().Set("WWW-Authenticate", fmt.Sprintf(`%s realm=%q, error=%q, error_description=%q`, HMACScheme, handler.realm, errorCode, authErr.Reason))
 	default:
-		// try the 'access_token' query param
-		req.ParseForm()
-		if len(req.Form["access_token"]) == 0 || req.Form["access_token"][0] != handler.token {
-			unauthorized = true
-			reason = "Mising or invalid access_token"
-		}
+		rw.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm=%q, error=%q, error_description=%q`, handler.realm, errorCode, authErr.Reason))
 	}
+}
 
-	return
+// Option customizes an AuthHandler created by NewAuthHandler.
+type Option func(*AuthHandler)
+
+// WithRealm sets the realm advertised in WWW-Authenticate challenges.
+// Defaults to "firehose".
+func WithRealm(realm string) Option {
+	return func(handler *AuthHandler) {
+		handler.realm = realm
+	}
+}
+
+// WithAuditLogger sets the AuditLogger invoked on every auth decision.
+// Defaults to LogAuditLogger{}.
+func WithAuditLogger(audit AuditLogger) Option {
+	return func(handler *AuthHandler) {
+		handler.audit = audit
+	}
 }
 
 // Middleware factory
-func NewAuthHandler(app http.Handler, token string) (handler *AuthHandler) {
+func NewAuthHandler(app http.Handler, authenticators []Authenticator, authorizers []Authorizer, opts ...Option) (handler *AuthHandler) {
 
 	handler = &AuthHandler{
-		app:   app,
-		token: token,
+		app:            app,
+		authenticators: authenticators,
+		authorizers:    authorizers,
+		realm:          defaultRealm,
+		audit:          LogAuditLogger{},
+	}
+
+	for _, opt := range opts {
+		opt(handler)
 	}
 
 	return
@@ -100,18 +184,30 @@ func Parse(value string) (scheme, credentials string, err error) {
 	return "", "", errors.New("The authorization header is malformed.")
 }
 
-// NewBasic parses credentials from a "basic" http authentication scheme.
+// NewBasic parses credentials from a "basic" http authentication scheme,
+// per RFC 7617: the decoded octets are UTF-8, the user-id and password are
+// split on the *first* colon only (a password may itself contain a colon),
+// and an empty user-id is rejected.
+//
+//    https://tools.ietf.org/html/rfc7617
 func NewBasic(credentials string) (*Basic, error) {
-	if b, err := base64.StdEncoding.DecodeString(credentials); err == nil {
-		parts := strings.Split(string(b), ":")
-		if len(parts) == 2 {
-			return &Basic{
-				Username: parts[0],
-				Password: parts[1],
-			}, nil
-		}
+	decoded, err := base64.StdEncoding.DecodeString(credentials)
+	if err != nil {
+		return nil, &AuthError{Kind: ErrMalformedCredentials, Scheme: "Basic", Reason: "The basic authentication header is not valid base64."}
 	}
-	return nil, errors.New("The basic authentication header is malformed.")
+	if !utf8.Valid(decoded) {
+		return nil, &AuthError{Kind: ErrMalformedCredentials, Scheme: "Basic", Reason: "The basic authentication header is not valid UTF-8."}
+	}
+
+	parts := strings.SplitN(string(decoded), ":", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return nil, &AuthError{Kind: ErrMalformedCredentials, Scheme: "Basic", Reason: "The basic authentication header is malformed."}
+	}
+
+	return &Basic{
+		Username: parts[0],
+		Password: parts[1],
+	}, nil
 }
 
 // Basic stores username and password for the "basic" http authentication
@@ -0,0 +1,43 @@
+package firehose
+
+import (
+	"encoding/base64"
+	"testing"
+)
+
+func TestNewBasicRejectsEmptyUsername(t *testing.T) {
+	credentials := base64.StdEncoding.EncodeToString([]byte(":secret"))
+
+	if _, err := NewBasic(credentials); err == nil {
+		t.Fatal("expected an error for an empty username")
+	}
+}
+
+func TestNewBasicAllowsColonInPassword(t *testing.T) {
+	credentials := base64.StdEncoding.EncodeToString([]byte("alice:pa:ss:word"))
+
+	basic, err := NewBasic(credentials)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if basic.Username != "alice" {
+		t.Fatalf("expected username %q, got %q", "alice", basic.Username)
+	}
+	if basic.Password != "pa:ss:word" {
+		t.Fatalf("expected password %q, got %q", "pa:ss:word", basic.Password)
+	}
+}
+
+func TestNewBasicRejectsInvalidBase64(t *testing.T) {
+	if _, err := NewBasic("not valid base64!!"); err == nil {
+		t.Fatal("expected an error for invalid base64")
+	}
+}
+
+func TestNewBasicRejectsInvalidUTF8(t *testing.T) {
+	credentials := base64.StdEncoding.EncodeToString([]byte{0xff, 0xfe, 0xfd})
+
+	if _, err := NewBasic(credentials); err == nil {
+		t.Fatal("expected an error for invalid UTF-8")
+	}
+}
@@ -0,0 +1,78 @@
+package firehose
+
+import "net/http"
+
+// Authenticator resolves an *http.Request into a Principal. Returning
+// ok == false means this Authenticator does not apply to the request (e.g.
+// it uses a different scheme); err is only set when the request clearly
+// attempted this scheme but failed, so AuthHandler can report a precise
+// reason.
+type Authenticator interface {
+	Authenticate(req *http.Request) (principal *Principal, ok bool, err error)
+}
+
+// BearerAuthenticator authenticates requests carrying an
+// "Authorization: Bearer <token>" header against a TokenStore.
+type BearerAuthenticator struct {
+	Store TokenStore
+}
+
+// Authenticate implements Authenticator.
+func (a BearerAuthenticator) Authenticate(req *http.Request) (*Principal, bool, error) {
+	scheme, credentials, err := ParseRequest(req)
+	if err != nil || scheme != "Bearer" {
+		return nil, false, nil
+	}
+	principal, ok := a.Store.Lookup(credentials)
+	if !ok {
+		return nil, false, &AuthError{Kind: ErrInvalidCredentials, Scheme: "Bearer", Reason: "Invalid or expired access token"}
+	}
+	principal.Scheme = "Bearer"
+	return principal, true, nil
+}
+
+// BasicAuthenticator authenticates requests using HTTP Basic
+// Authentication, looking the password up as a token in the TokenStore.
+type BasicAuthenticator struct {
+	Store TokenStore
+}
+
+// Authenticate implements Authenticator.
+func (a BasicAuthenticator) Authenticate(req *http.Request) (*Principal, bool, error) {
+	scheme, credentials, err := ParseRequest(req)
+	if err != nil || scheme != "Basic" {
+		return nil, false, nil
+	}
+	basic, err := NewBasic(credentials)
+	if err != nil {
+		return nil, false, err
+	}
+	principal, ok := a.Store.Lookup(basic.Password)
+	if !ok {
+		return nil, false, &AuthError{Kind: ErrInvalidCredentials, Scheme: "Basic", Reason: "Invalid credentials"}
+	}
+	principal.Scheme = "Basic"
+	return principal, true, nil
+}
+
+// QueryTokenAuthenticator authenticates requests carrying an "access_token"
+// query string parameter, for clients (such as the browser's EventSource
+// API) that cannot set arbitrary headers.
+type QueryTokenAuthenticator struct {
+	Store TokenStore
+}
+
+// Authenticate implements Authenticator.
+func (a QueryTokenAuthenticator) Authenticate(req *http.Request) (*Principal, bool, error) {
+	req.ParseForm()
+	token := req.Form.Get("access_token")
+	if token == "" {
+		return nil, false, nil
+	}
+	principal, ok := a.Store.Lookup(token)
+	if !ok {
+		return nil, false, &AuthError{Kind: ErrInvalidCredentials, Scheme: "QueryToken", Reason: "Missing or invalid access_token"}
+	}
+	principal.Scheme = "QueryToken"
+	return principal, true, nil
+}
@@ -0,0 +1,84 @@
+package firehose
+
+import (
+	"net"
+	"net/http"
+	"strings"
+)
+
+// Authorizer decides whether an already-authenticated Principal may
+// proceed with a request. When an AuthHandler has several Authorizers, all
+// of them must pass.
+type Authorizer interface {
+	Authorize(principal *Principal, req *http.Request) (ok bool, reason string)
+}
+
+// RoleAuthorizer requires the Principal to carry at least one of Scopes.
+type RoleAuthorizer struct {
+	Scopes []string
+}
+
+// Authorize implements Authorizer.
+func (a RoleAuthorizer) Authorize(principal *Principal, req *http.Request) (bool, string) {
+	for _, scope := range a.Scopes {
+		if principal.HasScope(scope) {
+			return true, ""
+		}
+	}
+	return false, "Principal lacks required scope"
+}
+
+// IPAllowlistAuthorizer requires the request's remote address to fall
+// within one of Allowed.
+type IPAllowlistAuthorizer struct {
+	Allowed []*net.IPNet
+}
+
+// Authorize implements Authorizer.
+func (a IPAllowlistAuthorizer) Authorize(principal *Principal, req *http.Request) (bool, string) {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		host = req.RemoteAddr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false, "Could not parse remote address"
+	}
+	for _, block := range a.Allowed {
+		if block.Contains(ip) {
+			return true, ""
+		}
+	}
+	return false, "Remote address not allowlisted"
+}
+
+// PathPrefixAuthorizer requires the request path to start with one of
+// Prefixes.
+type PathPrefixAuthorizer struct {
+	Prefixes []string
+}
+
+// Authorize implements Authorizer.
+func (a PathPrefixAuthorizer) Authorize(principal *Principal, req *http.Request) (bool, string) {
+	for _, prefix := range a.Prefixes {
+		if strings.HasPrefix(req.URL.Path, prefix) {
+			return true, ""
+		}
+	}
+	return false, "Path not permitted for this route"
+}
+
+// MethodAuthorizer requires the request method to be one of Methods.
+type MethodAuthorizer struct {
+	Methods []string
+}
+
+// Authorize implements Authorizer.
+func (a MethodAuthorizer) Authorize(principal *Principal, req *http.Request) (bool, string) {
+	for _, method := range a.Methods {
+		if req.Method == method {
+			return true, ""
+		}
+	}
+	return false, "Method not permitted for this route"
+}
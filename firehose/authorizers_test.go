@@ -0,0 +1,72 @@
+package firehose
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRoleAuthorizer(t *testing.T) {
+	authorizer := RoleAuthorizer{Scopes: []string{"read", "write"}}
+	req := httptest.NewRequest(http.MethodGet, "/stream", nil)
+
+	if ok, _ := authorizer.Authorize(&Principal{Scopes: []string{"write"}}, req); !ok {
+		t.Fatal("expected a principal with one matching scope to be authorized")
+	}
+	if ok, _ := authorizer.Authorize(&Principal{Scopes: []string{"admin"}}, req); ok {
+		t.Fatal("expected a principal with no matching scope to be denied")
+	}
+}
+
+func TestIPAllowlistAuthorizer(t *testing.T) {
+	_, allowed, err := net.ParseCIDR("10.0.0.0/8")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	authorizer := IPAllowlistAuthorizer{Allowed: []*net.IPNet{allowed}}
+
+	req := httptest.NewRequest(http.MethodGet, "/stream", nil)
+	req.RemoteAddr = "10.1.2.3:4567"
+	if ok, _ := authorizer.Authorize(&Principal{}, req); !ok {
+		t.Fatal("expected a remote address within the allowlisted block to be authorized")
+	}
+
+	req.RemoteAddr = "192.168.1.1:4567"
+	if ok, _ := authorizer.Authorize(&Principal{}, req); ok {
+		t.Fatal("expected a remote address outside the allowlisted block to be denied")
+	}
+
+	req.RemoteAddr = "not-an-address"
+	if ok, _ := authorizer.Authorize(&Principal{}, req); ok {
+		t.Fatal("expected an unparseable remote address to be denied")
+	}
+}
+
+func TestPathPrefixAuthorizer(t *testing.T) {
+	authorizer := PathPrefixAuthorizer{Prefixes: []string{"/stream/"}}
+
+	req := httptest.NewRequest(http.MethodGet, "/stream/orders", nil)
+	if ok, _ := authorizer.Authorize(&Principal{}, req); !ok {
+		t.Fatal("expected a path with a matching prefix to be authorized")
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/admin", nil)
+	if ok, _ := authorizer.Authorize(&Principal{}, req); ok {
+		t.Fatal("expected a path without a matching prefix to be denied")
+	}
+}
+
+func TestMethodAuthorizer(t *testing.T) {
+	authorizer := MethodAuthorizer{Methods: []string{http.MethodGet}}
+
+	req := httptest.NewRequest(http.MethodGet, "/stream", nil)
+	if ok, _ := authorizer.Authorize(&Principal{}, req); !ok {
+		t.Fatal("expected an allowed method to be authorized")
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/stream", nil)
+	if ok, _ := authorizer.Authorize(&Principal{}, req); ok {
+		t.Fatal("expected a disallowed method to be denied")
+	}
+}
@@ -0,0 +1,35 @@
+package firehose
+
+import (
+	"context"
+	"net/http"
+)
+
+// BearerValidator validates an opaque OAuth2 bearer token against an
+// external authorization server and resolves it to a Principal.
+type BearerValidator interface {
+	Validate(ctx context.Context, token string) (*Principal, error)
+}
+
+// OAuth2BearerAuthenticator is an Authenticator that delegates bearer
+// token validation to a BearerValidator, for OAuth2 access tokens issued
+// by an external authorization server rather than the package's own
+// TokenStore.
+type OAuth2BearerAuthenticator struct {
+	Validator BearerValidator
+}
+
+// Authenticate implements Authenticator.
+func (a OAuth2BearerAuthenticator) Authenticate(req *http.Request) (*Principal, bool, error) {
+	scheme, credentials, err := ParseRequest(req)
+	if err != nil || scheme != "Bearer" {
+		return nil, false, nil
+	}
+
+	principal, err := a.Validator.Validate(req.Context(), credentials)
+	if err != nil {
+		return nil, false, &AuthError{Kind: ErrInvalidCredentials, Scheme: "Bearer", Reason: err.Error()}
+	}
+	principal.Scheme = "Bearer"
+	return principal, true, nil
+}
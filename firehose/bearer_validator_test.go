@@ -0,0 +1,64 @@
+package firehose
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type fakeBearerValidator struct {
+	principal *Principal
+	err       error
+}
+
+func (v fakeBearerValidator) Validate(ctx context.Context, token string) (*Principal, error) {
+	if v.err != nil {
+		return nil, v.err
+	}
+	principal := *v.principal
+	return &principal, nil
+}
+
+func TestOAuth2BearerAuthenticatorAcceptsValidToken(t *testing.T) {
+	auth := OAuth2BearerAuthenticator{Validator: fakeBearerValidator{principal: &Principal{Label: "alice"}}}
+
+	req := httptest.NewRequest(http.MethodGet, "/stream", nil)
+	req.Header.Set("Authorization", "Bearer valid-token")
+
+	principal, ok, err := auth.Authenticate(req)
+	if err != nil || !ok {
+		t.Fatalf("expected success, got ok=%v err=%v", ok, err)
+	}
+	if principal.Label != "alice" {
+		t.Fatalf("expected label %q, got %q", "alice", principal.Label)
+	}
+	if principal.Scheme != "Bearer" {
+		t.Fatalf("expected scheme %q, got %q", "Bearer", principal.Scheme)
+	}
+}
+
+func TestOAuth2BearerAuthenticatorRejectsInvalidToken(t *testing.T) {
+	auth := OAuth2BearerAuthenticator{Validator: fakeBearerValidator{err: errors.New("token rejected")}}
+
+	req := httptest.NewRequest(http.MethodGet, "/stream", nil)
+	req.Header.Set("Authorization", "Bearer bad-token")
+
+	_, ok, err := auth.Authenticate(req)
+	if ok || err == nil {
+		t.Fatal("expected a validation failure to be rejected")
+	}
+}
+
+func TestOAuth2BearerAuthenticatorIgnoresOtherSchemes(t *testing.T) {
+	auth := OAuth2BearerAuthenticator{Validator: fakeBearerValidator{principal: &Principal{Label: "alice"}}}
+
+	req := httptest.NewRequest(http.MethodGet, "/stream", nil)
+	req.Header.Set("Authorization", "Basic dXNlcjpwYXNz")
+
+	principal, ok, err := auth.Authenticate(req)
+	if principal != nil || ok || err != nil {
+		t.Fatalf("expected a non-Bearer scheme to be ignored, got principal=%v ok=%v err=%v", principal, ok, err)
+	}
+}
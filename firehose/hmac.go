@@ -0,0 +1,81 @@
+package firehose
+
+import (
+	"encoding/base64"
+	"strings"
+)
+
+// HMACScheme is the Authorization scheme name used by signed
+// server-to-server firehose requests.
+const HMACScheme = "BooticHMAC"
+
+// defaultHMACHeaders is used when a "BooticHMAC" credential omits the
+// "headers" attribute. It matches requiredHMACHeaders (see
+// hmac_authenticator.go) plus "date", so requests signed with the default
+// still carry a timestamp for the replay-window check.
+var defaultHMACHeaders = []string{"(request-target)", "host", "date"}
+
+// HMAC stores the fields parsed from a "BooticHMAC" authentication scheme,
+// e.g.
+//
+//	BooticHMAC keyId="prod-consumer",signature="base64...",headers="(request-target) host date",algorithm="hmac-sha256"
+//
+// Algorithm is checked by HMACAuthenticator, which only supports
+// "hmac-sha256" and rejects anything else.
+type HMAC struct {
+	KeyID     string
+	Signature []byte
+	Headers   []string
+	Algorithm string
+}
+
+// NewHMAC parses credentials from a "BooticHMAC" authentication scheme,
+// analogous to NewBasic.
+func NewHMAC(credentials string) (*HMAC, error) {
+	params := parseAuthParams(credentials)
+
+	keyID := params["keyId"]
+	if keyID == "" {
+		return nil, &AuthError{Kind: ErrMalformedCredentials, Scheme: HMACScheme, Reason: "BooticHMAC credentials are missing keyId"}
+	}
+
+	signatureB64 := params["signature"]
+	if signatureB64 == "" {
+		return nil, &AuthError{Kind: ErrMalformedCredentials, Scheme: HMACScheme, Reason: "BooticHMAC credentials are missing signature"}
+	}
+	signature, err := base64.StdEncoding.DecodeString(signatureB64)
+	if err != nil {
+		return nil, &AuthError{Kind: ErrMalformedCredentials, Scheme: HMACScheme, Reason: "BooticHMAC signature is not valid base64"}
+	}
+
+	algorithm := params["algorithm"]
+	if algorithm == "" {
+		algorithm = "hmac-sha256"
+	}
+
+	headers := strings.Fields(params["headers"])
+	if len(headers) == 0 {
+		headers = defaultHMACHeaders
+	}
+
+	return &HMAC{
+		KeyID:     keyID,
+		Signature: signature,
+		Headers:   headers,
+		Algorithm: algorithm,
+	}, nil
+}
+
+// parseAuthParams parses the comma separated list of key="value" pairs
+// used by the BooticHMAC scheme's credentials.
+func parseAuthParams(credentials string) map[string]string {
+	params := map[string]string{}
+	for _, part := range strings.Split(credentials, ",") {
+		kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		params[kv[0]] = strings.Trim(kv[1], `"`)
+	}
+	return params
+}
@@ -0,0 +1,89 @@
+package firehose
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHMACAuthenticatorAcceptsValidSignature(t *testing.T) {
+	auth := HMACAuthenticator{Store: MapKeyStore{"key1": "secret"}}
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/stream", nil)
+	SignHMACRequest(req, "key1", "secret", nil)
+
+	principal, ok, err := auth.Authenticate(req)
+	if err != nil || !ok {
+		t.Fatalf("expected success, got ok=%v err=%v", ok, err)
+	}
+	if principal.Label != "key1" {
+		t.Fatalf("expected label %q, got %q", "key1", principal.Label)
+	}
+	if principal.Scheme != HMACScheme {
+		t.Fatalf("expected scheme %q, got %q", HMACScheme, principal.Scheme)
+	}
+}
+
+func TestHMACAuthenticatorRejectsBadSignature(t *testing.T) {
+	auth := HMACAuthenticator{Store: MapKeyStore{"key1": "secret"}}
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/stream", nil)
+	SignHMACRequest(req, "key1", "wrong-secret", nil)
+
+	_, ok, err := auth.Authenticate(req)
+	if ok || err == nil {
+		t.Fatal("expected a signature verification failure")
+	}
+}
+
+func TestHMACAuthenticatorRejectsClockSkew(t *testing.T) {
+	auth := HMACAuthenticator{Store: MapKeyStore{"key1": "secret"}, MaxSkew: time.Minute}
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/stream", nil)
+	req.Header.Set("Date", time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat))
+	SignHMACRequest(req, "key1", "secret", nil)
+
+	_, ok, err := auth.Authenticate(req)
+	if ok || err == nil {
+		t.Fatal("expected a clock skew rejection")
+	}
+}
+
+func TestHMACAuthenticatorRejectsMissingRequestTarget(t *testing.T) {
+	auth := HMACAuthenticator{Store: MapKeyStore{"key1": "secret"}}
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/stream", nil)
+	SignHMACRequest(req, "key1", "secret", []string{"date"})
+
+	_, ok, err := auth.Authenticate(req)
+	if ok || err == nil {
+		t.Fatal("expected rejection for a signature that does not cover (request-target)")
+	}
+}
+
+func TestHMACAuthenticatorRejectsUnsupportedAlgorithm(t *testing.T) {
+	auth := HMACAuthenticator{Store: MapKeyStore{"key1": "secret"}}
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/stream", nil)
+	SignHMACRequest(req, "key1", "secret", nil)
+	req.Header.Set("Authorization", strings.Replace(req.Header.Get("Authorization"), `algorithm="hmac-sha256"`, `algorithm="hmac-sha1"`, 1))
+
+	_, ok, err := auth.Authenticate(req)
+	if ok || err == nil {
+		t.Fatal("expected rejection for an unsupported algorithm")
+	}
+}
+
+func TestHMACAuthenticatorRejectsUnknownKeyID(t *testing.T) {
+	auth := HMACAuthenticator{Store: MapKeyStore{"key1": "secret"}}
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/stream", nil)
+	SignHMACRequest(req, "unknown-key", "secret", nil)
+
+	_, ok, err := auth.Authenticate(req)
+	if ok || err == nil {
+		t.Fatal("expected rejection for an unknown keyId")
+	}
+}
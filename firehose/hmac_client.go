@@ -0,0 +1,36 @@
+package firehose
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// SignHMACRequest signs req for the "BooticHMAC" scheme using keyID and
+// secret, setting a Date header (if not already present) and the
+// resulting Authorization header. Firehose subscribers call this to sign
+// their EventSource/SSE handshake before dialing. Headers defaults to
+// defaultHMACHeaders when empty, which covers requiredHMACHeaders (the
+// minimum HMACAuthenticator accepts) plus "date", so the signature commits
+// to the method and path, not just a replayable timestamp.
+func SignHMACRequest(req *http.Request, keyID, secret string, headers []string) {
+	if req.Header.Get("Date") == "" {
+		req.Header.Set("Date", time.Now().UTC().Format(http.TimeFormat))
+	}
+	if len(headers) == 0 {
+		headers = defaultHMACHeaders
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(canonicalizeSignedHeaders(req, headers)))
+	signature := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		`%s keyId="%s",signature="%s",headers="%s",algorithm="hmac-sha256"`,
+		HMACScheme, keyID, signature, strings.Join(headers, " "),
+	))
+}
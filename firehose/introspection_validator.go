@@ -0,0 +1,118 @@
+package firehose
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// IntrospectionValidator is a BearerValidator that validates tokens via
+// RFC 7662 OAuth2 token introspection, POSTing the token to Endpoint with
+// client credentials and caching the response for CacheTTL.
+//
+//    https://tools.ietf.org/html/rfc7662
+type IntrospectionValidator struct {
+	// Endpoint is the introspection endpoint URL.
+	Endpoint string
+	// ClientID and ClientSecret authenticate this server to Endpoint.
+	ClientID     string
+	ClientSecret string
+	// HTTPClient is used to call Endpoint. Defaults to http.DefaultClient.
+	HTTPClient *http.Client
+	// CacheTTL bounds how long a response is cached. Defaults to one
+	// minute.
+	CacheTTL time.Duration
+
+	mu    sync.Mutex
+	cache map[string]introspectionCacheEntry
+}
+
+type introspectionCacheEntry struct {
+	response introspectionResponse
+	expires  time.Time
+}
+
+type introspectionResponse struct {
+	Active  bool   `json:"active"`
+	Scope   string `json:"scope"`
+	Subject string `json:"sub"`
+	Expiry  int64  `json:"exp"`
+}
+
+// Validate implements BearerValidator.
+func (v *IntrospectionValidator) Validate(ctx context.Context, token string) (*Principal, error) {
+	resp, err := v.introspect(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Active {
+		return nil, fmt.Errorf("token is not active")
+	}
+	if resp.Expiry != 0 && time.Now().Unix() >= resp.Expiry {
+		return nil, fmt.Errorf("token has expired")
+	}
+
+	principal := &Principal{Label: resp.Subject}
+	if resp.Scope != "" {
+		principal.Scopes = strings.Fields(resp.Scope)
+	}
+	return principal, nil
+}
+
+// introspect returns the cached introspection response for token, fetching
+// a fresh one from v.Endpoint when the cache is empty or stale.
+func (v *IntrospectionValidator) introspect(ctx context.Context, token string) (introspectionResponse, error) {
+	v.mu.Lock()
+	entry, ok := v.cache[token]
+	v.mu.Unlock()
+	if ok && time.Now().Before(entry.expires) {
+		return entry.response, nil
+	}
+
+	client := v.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	form := url.Values{"token": {token}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, v.Endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return introspectionResponse{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(v.ClientID, v.ClientSecret)
+
+	res, err := client.Do(req)
+	if err != nil {
+		return introspectionResponse{}, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return introspectionResponse{}, fmt.Errorf("introspection endpoint returned %s", res.Status)
+	}
+
+	var parsed introspectionResponse
+	if err := json.NewDecoder(res.Body).Decode(&parsed); err != nil {
+		return introspectionResponse{}, err
+	}
+
+	ttl := v.CacheTTL
+	if ttl <= 0 {
+		ttl = time.Minute
+	}
+
+	v.mu.Lock()
+	if v.cache == nil {
+		v.cache = map[string]introspectionCacheEntry{}
+	}
+	v.cache[token] = introspectionCacheEntry{response: parsed, expires: time.Now().Add(ttl)}
+	v.mu.Unlock()
+
+	return parsed, nil
+}
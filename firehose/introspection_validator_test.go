@@ -0,0 +1,76 @@
+package firehose
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func introspectionServer(t *testing.T, resp introspectionResponse) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(rw).Encode(resp)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestIntrospectionValidatorAcceptsActiveToken(t *testing.T) {
+	server := introspectionServer(t, introspectionResponse{Active: true, Subject: "alice", Scope: "read write"})
+	validator := &IntrospectionValidator{Endpoint: server.URL}
+
+	principal, err := validator.Validate(context.Background(), "valid-token")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if principal.Label != "alice" {
+		t.Fatalf("expected label %q, got %q", "alice", principal.Label)
+	}
+	if len(principal.Scopes) != 2 || principal.Scopes[0] != "read" || principal.Scopes[1] != "write" {
+		t.Fatalf("expected scopes [read write], got %v", principal.Scopes)
+	}
+}
+
+func TestIntrospectionValidatorRejectsInactiveToken(t *testing.T) {
+	server := introspectionServer(t, introspectionResponse{Active: false})
+	validator := &IntrospectionValidator{Endpoint: server.URL}
+
+	if _, err := validator.Validate(context.Background(), "revoked-token"); err == nil {
+		t.Fatal("expected an inactive token to be rejected")
+	}
+}
+
+func TestIntrospectionValidatorRejectsExpiredToken(t *testing.T) {
+	server := introspectionServer(t, introspectionResponse{Active: true, Subject: "alice", Expiry: time.Now().Add(-time.Minute).Unix()})
+	validator := &IntrospectionValidator{Endpoint: server.URL}
+
+	if _, err := validator.Validate(context.Background(), "expired-token"); err == nil {
+		t.Fatal("expected an expired token to be rejected")
+	}
+}
+
+func TestIntrospectionValidatorCachesResponse(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		calls++
+		rw.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(rw).Encode(introspectionResponse{Active: true, Subject: "alice"})
+	}))
+	defer server.Close()
+
+	validator := &IntrospectionValidator{Endpoint: server.URL, CacheTTL: time.Minute}
+
+	if _, err := validator.Validate(context.Background(), "tok"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := validator.Validate(context.Background(), "tok"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected the second call to be served from cache, got %d introspection requests", calls)
+	}
+}
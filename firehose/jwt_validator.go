@@ -0,0 +1,247 @@
+package firehose
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// JWTValidator is a BearerValidator that validates RS256-signed JWT bearer
+// tokens against public keys published at a JWKS endpoint, checking
+// signature, expiry, issuer and audience and extracting claims.
+type JWTValidator struct {
+	// JWKSURL is fetched to resolve a token's "kid" to a public key.
+	JWKSURL string
+	// Issuer, if set, must match the token's "iss" claim.
+	Issuer string
+	// Audience, if set, must appear in the token's "aud" claim.
+	Audience string
+	// HTTPClient is used to fetch JWKSURL. Defaults to http.DefaultClient.
+	HTTPClient *http.Client
+	// KeySetTTL bounds how long a fetched key set is cached. Defaults to
+	// ten minutes.
+	KeySetTTL time.Duration
+
+	mu        sync.Mutex
+	keys      map[string]*rsa.PublicKey
+	fetchedAt time.Time
+}
+
+type jwtHeader struct {
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+}
+
+type jwtClaims struct {
+	Subject   string      `json:"sub"`
+	Issuer    string      `json:"iss"`
+	Audience  jwtAudience `json:"aud"`
+	ExpiresAt int64       `json:"exp"`
+	Scope     string      `json:"scope"`
+}
+
+// jwtAudience accepts both the single-string and array-of-strings forms
+// that RFC 7519 allows for the "aud" claim.
+type jwtAudience []string
+
+func (a *jwtAudience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = jwtAudience{single}
+		return nil
+	}
+	var multi []string
+	if err := json.Unmarshal(data, &multi); err != nil {
+		return err
+	}
+	*a = jwtAudience(multi)
+	return nil
+}
+
+func (a jwtAudience) has(audience string) bool {
+	for _, candidate := range a {
+		if candidate == audience {
+			return true
+		}
+	}
+	return false
+}
+
+type jsonWebKeySet struct {
+	Keys []jsonWebKey `json:"keys"`
+}
+
+type jsonWebKey struct {
+	KeyID    string `json:"kid"`
+	KeyType  string `json:"kty"`
+	Modulus  string `json:"n"`
+	Exponent string `json:"e"`
+}
+
+// Validate implements BearerValidator.
+func (v *JWTValidator) Validate(ctx context.Context, token string) (*Principal, error) {
+	header, claims, signedPart, signature, err := parseJWT(token)
+	if err != nil {
+		return nil, err
+	}
+	if header.Algorithm != "RS256" {
+		return nil, fmt.Errorf("unsupported jwt algorithm %q", header.Algorithm)
+	}
+
+	key, err := v.key(ctx, header.KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	hashed := sha256.Sum256([]byte(signedPart))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, hashed[:], signature); err != nil {
+		return nil, fmt.Errorf("jwt signature verification failed")
+	}
+
+	if claims.ExpiresAt != 0 && time.Now().Unix() >= claims.ExpiresAt {
+		return nil, fmt.Errorf("jwt has expired")
+	}
+	if v.Issuer != "" && claims.Issuer != v.Issuer {
+		return nil, fmt.Errorf("jwt issuer mismatch")
+	}
+	if v.Audience != "" && !claims.Audience.has(v.Audience) {
+		return nil, fmt.Errorf("jwt audience mismatch")
+	}
+
+	principal := &Principal{Label: claims.Subject}
+	if claims.Scope != "" {
+		principal.Scopes = strings.Fields(claims.Scope)
+	}
+	return principal, nil
+}
+
+// key resolves keyID to a public key, fetching and caching v.JWKSURL as
+// needed.
+func (v *JWTValidator) key(ctx context.Context, keyID string) (*rsa.PublicKey, error) {
+	v.mu.Lock()
+	ttl := v.KeySetTTL
+	if ttl <= 0 {
+		ttl = 10 * time.Minute
+	}
+	stale := v.keys == nil || time.Now().After(v.fetchedAt.Add(ttl))
+	v.mu.Unlock()
+
+	if stale {
+		if err := v.fetchKeys(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	v.mu.Lock()
+	key, ok := v.keys[keyID]
+	v.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("no jwks key found for kid %q", keyID)
+	}
+	return key, nil
+}
+
+func (v *JWTValidator) fetchKeys(ctx context.Context) error {
+	client := v.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.JWKSURL, nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("jwks endpoint returned %s", res.Status)
+	}
+
+	var set jsonWebKeySet
+	if err := json.NewDecoder(res.Body).Decode(&set); err != nil {
+		return err
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.KeyType != "RSA" {
+			continue
+		}
+		key, err := k.rsaPublicKey()
+		if err != nil {
+			return err
+		}
+		keys[k.KeyID] = key
+	}
+
+	v.mu.Lock()
+	v.keys = keys
+	v.fetchedAt = time.Now()
+	v.mu.Unlock()
+
+	return nil
+}
+
+// rsaPublicKey decodes the base64url-encoded modulus and exponent of a JWK
+// into an *rsa.PublicKey.
+func (k jsonWebKey) rsaPublicKey() (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(k.Modulus)
+	if err != nil {
+		return nil, fmt.Errorf("jwk %q has invalid modulus: %w", k.KeyID, err)
+	}
+	e, err := base64.RawURLEncoding.DecodeString(k.Exponent)
+	if err != nil {
+		return nil, fmt.Errorf("jwk %q has invalid exponent: %w", k.KeyID, err)
+	}
+
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(n),
+		E: int(new(big.Int).SetBytes(e).Int64()),
+	}, nil
+}
+
+// parseJWT splits a compact JWT into its header, claims, the part that was
+// signed (header.claims) and the decoded signature bytes.
+func parseJWT(token string) (header jwtHeader, claims jwtClaims, signedPart string, signature []byte, err error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return header, claims, "", nil, fmt.Errorf("jwt does not have three parts")
+	}
+
+	headerJSON, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return header, claims, "", nil, fmt.Errorf("jwt header is not valid base64url")
+	}
+	if err := json.Unmarshal(headerJSON, &header); err != nil {
+		return header, claims, "", nil, fmt.Errorf("jwt header is not valid JSON")
+	}
+
+	claimsJSON, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return header, claims, "", nil, fmt.Errorf("jwt claims are not valid base64url")
+	}
+	if err := json.Unmarshal(claimsJSON, &claims); err != nil {
+		return header, claims, "", nil, fmt.Errorf("jwt claims are not valid JSON")
+	}
+
+	signature, err = base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return header, claims, "", nil, fmt.Errorf("jwt signature is not valid base64url")
+	}
+
+	return header, claims, parts[0] + "." + parts[1], signature, nil
+}
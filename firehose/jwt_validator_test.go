@@ -0,0 +1,192 @@
+package firehose
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// signTestJWT builds a compact RS256 JWT signed with key for the given
+// claims, mirroring what a real OAuth2 authorization server would issue.
+func signTestJWT(t *testing.T, key *rsa.PrivateKey, kid string, claims map[string]interface{}) string {
+	t.Helper()
+
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "kid": kid})
+	if err != nil {
+		t.Fatalf("marshal header: %v", err)
+	}
+	body, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatalf("marshal claims: %v", err)
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(body)
+	hashed := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hashed[:])
+	if err != nil {
+		t.Fatalf("sign jwt: %v", err)
+	}
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+// flipChar returns s with the byte at index replaced by a different one,
+// guaranteeing the result decodes to different bytes (unlike flipping the
+// very last base64 character of a segment, whose low bits can be padding
+// that decoders ignore).
+func flipChar(s string, index int) string {
+	replacement := byte('A')
+	if s[index] == 'A' {
+		replacement = 'B'
+	}
+	return s[:index] + string(replacement) + s[index+1:]
+}
+
+// newTestJWKSServer serves a single RSA key as a JWKS document.
+func newTestJWKSServer(t *testing.T, key *rsa.PublicKey, kid string) *httptest.Server {
+	t.Helper()
+
+	n := base64.RawURLEncoding.EncodeToString(key.N.Bytes())
+	e := base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes())
+	body, err := json.Marshal(map[string]interface{}{
+		"keys": []map[string]string{{"kid": kid, "kty": "RSA", "n": n, "e": e}},
+	})
+	if err != nil {
+		t.Fatalf("marshal jwks: %v", err)
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Write(body)
+	}))
+}
+
+func TestJWTValidatorAcceptsValidToken(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	server := newTestJWKSServer(t, &key.PublicKey, "kid1")
+	defer server.Close()
+
+	validator := &JWTValidator{JWKSURL: server.URL, Issuer: "https://issuer.example", Audience: "firehose"}
+	token := signTestJWT(t, key, "kid1", map[string]interface{}{
+		"sub":   "user-1",
+		"iss":   "https://issuer.example",
+		"aud":   "firehose",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"scope": "read write",
+	})
+
+	principal, err := validator.Validate(context.Background(), token)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if principal.Label != "user-1" {
+		t.Fatalf("expected label %q, got %q", "user-1", principal.Label)
+	}
+	if !principal.HasScope("read") || !principal.HasScope("write") {
+		t.Fatalf("expected scopes read and write, got %v", principal.Scopes)
+	}
+}
+
+func TestJWTValidatorRejectsTamperedSignature(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	server := newTestJWKSServer(t, &key.PublicKey, "kid1")
+	defer server.Close()
+
+	validator := &JWTValidator{JWKSURL: server.URL}
+	token := signTestJWT(t, key, "kid1", map[string]interface{}{
+		"sub": "user-1",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	})
+	tampered := flipChar(token, len(token)/2)
+
+	if _, err := validator.Validate(context.Background(), tampered); err == nil {
+		t.Fatal("expected an error for a tampered signature")
+	}
+}
+
+func TestJWTValidatorRejectsExpiredToken(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	server := newTestJWKSServer(t, &key.PublicKey, "kid1")
+	defer server.Close()
+
+	validator := &JWTValidator{JWKSURL: server.URL}
+	token := signTestJWT(t, key, "kid1", map[string]interface{}{
+		"sub": "user-1",
+		"exp": time.Now().Add(-time.Hour).Unix(),
+	})
+
+	if _, err := validator.Validate(context.Background(), token); err == nil {
+		t.Fatal("expected an error for an expired token")
+	}
+}
+
+func TestJWTValidatorRejectsWrongAudience(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	server := newTestJWKSServer(t, &key.PublicKey, "kid1")
+	defer server.Close()
+
+	validator := &JWTValidator{JWKSURL: server.URL, Audience: "firehose"}
+	token := signTestJWT(t, key, "kid1", map[string]interface{}{
+		"sub": "user-1",
+		"aud": "some-other-service",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	})
+
+	if _, err := validator.Validate(context.Background(), token); err == nil {
+		t.Fatal("expected an error for a mismatched audience")
+	}
+}
+
+func TestJWTValidatorRejectsWrongIssuer(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	server := newTestJWKSServer(t, &key.PublicKey, "kid1")
+	defer server.Close()
+
+	validator := &JWTValidator{JWKSURL: server.URL, Issuer: "https://issuer.example"}
+	token := signTestJWT(t, key, "kid1", map[string]interface{}{
+		"sub": "user-1",
+		"iss": "https://attacker.example",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	})
+
+	if _, err := validator.Validate(context.Background(), token); err == nil {
+		t.Fatal("expected an error for a mismatched issuer")
+	}
+}
+
+func TestJWTValidatorRejectsUnsupportedAlgorithm(t *testing.T) {
+	header, _ := json.Marshal(map[string]string{"alg": "HS256", "kid": "kid1"})
+	claims, _ := json.Marshal(map[string]interface{}{"sub": "user-1"})
+	token := base64.RawURLEncoding.EncodeToString(header) + "." +
+		base64.RawURLEncoding.EncodeToString(claims) + "." +
+		base64.RawURLEncoding.EncodeToString([]byte("not-a-real-signature"))
+
+	validator := &JWTValidator{JWKSURL: "http://unused.invalid"}
+
+	if _, err := validator.Validate(context.Background(), token); err == nil {
+		t.Fatal("expected an error for an unsupported algorithm")
+	}
+}
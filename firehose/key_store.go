@@ -0,0 +1,16 @@
+package firehose
+
+// KeyStore resolves an HMAC keyId to its shared secret, used by
+// HMACAuthenticator to verify signed server-to-server requests.
+type KeyStore interface {
+	Lookup(keyID string) (secret string, ok bool)
+}
+
+// MapKeyStore is a KeyStore backed by an in-memory map of keyId to secret.
+type MapKeyStore map[string]string
+
+// Lookup implements KeyStore.
+func (s MapKeyStore) Lookup(keyID string) (string, bool) {
+	secret, ok := s[keyID]
+	return secret, ok
+}
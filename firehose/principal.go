@@ -0,0 +1,45 @@
+package firehose
+
+import "context"
+
+// contextKey is an unexported type to avoid collisions with context keys
+// defined in other packages.
+type contextKey int
+
+const principalContextKey contextKey = iota
+
+// Principal describes the identity resolved by an Authenticator. It is
+// attached to the request context so downstream handlers can inspect who
+// made the request without re-parsing credentials.
+type Principal struct {
+	// Label identifies the principal for logging and auditing, e.g. a
+	// token label, a JWT subject, or an HMAC key id.
+	Label string
+	// Scopes lists the permissions granted to the principal, used by
+	// Authorizers such as RoleAuthorizer.
+	Scopes []string
+	// Scheme is the authentication scheme that resolved this principal,
+	// e.g. "Bearer", "Basic" or HMACScheme, stamped by the Authenticator
+	// that succeeded. Used for audit logging.
+	Scheme string
+}
+
+// HasScope reports whether the principal was granted the given scope.
+func (p *Principal) HasScope(scope string) bool {
+	if p == nil {
+		return false
+	}
+	for _, s := range p.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// PrincipalFromContext returns the Principal attached to ctx by AuthHandler,
+// if any.
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(principalContextKey).(*Principal)
+	return principal, ok
+}
@@ -0,0 +1,104 @@
+package firehose
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// QuotaLimits bounds how much traffic a single principal may generate.
+// A zero field means that limit is not enforced.
+type QuotaLimits struct {
+	// MaxConcurrentStreams caps how many connections a principal may have
+	// open at once.
+	MaxConcurrentStreams int
+	// RequestsPerMinute caps how many requests a principal may start per
+	// rolling minute.
+	RequestsPerMinute int
+}
+
+// QuotaHandler is a sibling middleware to AuthHandler: because the
+// firehose exposes an unbounded SSE stream, a valid token could otherwise
+// open unlimited concurrent connections. QuotaHandler reads the Principal
+// left on the request context by AuthHandler and enforces per-principal
+// connection and rate quotas, returning 429 Too Many Requests with a
+// Retry-After header when a quota is exceeded. It should wrap the handler
+// passed to NewAuthHandler, so it runs after authentication.
+type QuotaHandler struct {
+	app    http.Handler
+	limits QuotaLimits
+
+	mu       sync.Mutex
+	streams  map[string]int
+	requests map[string][]time.Time
+}
+
+// NewQuotaHandler wraps app with per-principal quota enforcement.
+func NewQuotaHandler(app http.Handler, limits QuotaLimits) *QuotaHandler {
+	return &QuotaHandler{
+		app:      app,
+		limits:   limits,
+		streams:  map[string]int{},
+		requests: map[string][]time.Time{},
+	}
+}
+
+// ServeHTTP implements http.Handler.
+func (handler *QuotaHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	label := ""
+	if principal, ok := PrincipalFromContext(req.Context()); ok {
+		label = principal.Label
+	}
+
+	retryAfter, ok := handler.reserve(label)
+	if !ok {
+		rw.Header().Set("Retry-After", fmt.Sprintf("%d", retryAfter))
+		http.Error(rw, "Too Many Requests", http.StatusTooManyRequests)
+		return
+	}
+	defer handler.release(label)
+
+	handler.app.ServeHTTP(rw, req)
+}
+
+// reserve admits a request for label if it fits within both configured
+// quotas, incrementing the concurrent stream count on success. It returns
+// ok == false and a Retry-After in seconds when a quota is exceeded.
+func (handler *QuotaHandler) reserve(label string) (retryAfterSeconds int, ok bool) {
+	handler.mu.Lock()
+	defer handler.mu.Unlock()
+
+	if handler.limits.MaxConcurrentStreams > 0 && handler.streams[label] >= handler.limits.MaxConcurrentStreams {
+		return 1, false
+	}
+
+	if handler.limits.RequestsPerMinute > 0 {
+		cutoff := time.Now().Add(-time.Minute)
+		recent := handler.requests[label][:0]
+		for _, t := range handler.requests[label] {
+			if t.After(cutoff) {
+				recent = append(recent, t)
+			}
+		}
+		if len(recent) >= handler.limits.RequestsPerMinute {
+			handler.requests[label] = recent
+			retryAfterSeconds = int(time.Until(recent[0].Add(time.Minute)).Seconds()) + 1
+			return retryAfterSeconds, false
+		}
+		handler.requests[label] = append(recent, time.Now())
+	}
+
+	handler.streams[label]++
+	return 0, true
+}
+
+// release returns the concurrent-stream slot reserved by reserve.
+func (handler *QuotaHandler) release(label string) {
+	handler.mu.Lock()
+	defer handler.mu.Unlock()
+	handler.streams[label]--
+	if handler.streams[label] <= 0 {
+		delete(handler.streams, label)
+	}
+}
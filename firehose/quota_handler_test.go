@@ -0,0 +1,96 @@
+package firehose
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// withPrincipal attaches a Principal to req's context the way AuthHandler
+// would, so QuotaHandler (which runs after it in the chain) can read it.
+func withPrincipal(req *http.Request, label string) *http.Request {
+	ctx := context.WithValue(req.Context(), principalContextKey, &Principal{Label: label})
+	return req.WithContext(ctx)
+}
+
+func TestQuotaHandlerRejectsOverConcurrentStreamsAndReleasesOnReturn(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var once sync.Once
+	app := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		once.Do(func() {
+			close(started)
+			<-release
+		})
+	})
+	handler := NewQuotaHandler(app, QuotaLimits{MaxConcurrentStreams: 1})
+
+	done := make(chan struct{})
+	go func() {
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, withPrincipal(httptest.NewRequest(http.MethodGet, "/stream", nil), "alice"))
+		close(done)
+	}()
+	<-started
+
+	rw := httptest.NewRecorder()
+	handler.ServeHTTP(rw, withPrincipal(httptest.NewRequest(http.MethodGet, "/stream", nil), "alice"))
+	if rw.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected %d while the first stream is open, got %d", http.StatusTooManyRequests, rw.Code)
+	}
+	if rw.Header().Get("Retry-After") == "" {
+		t.Fatal("expected a Retry-After header")
+	}
+
+	close(release)
+	<-done
+
+	rw = httptest.NewRecorder()
+	handler.ServeHTTP(rw, withPrincipal(httptest.NewRequest(http.MethodGet, "/stream", nil), "alice"))
+	if rw.Code != http.StatusOK {
+		t.Fatalf("expected the slot to be released once the first stream returned, got %d", rw.Code)
+	}
+}
+
+func TestQuotaHandlerRejectsOverRequestsPerMinute(t *testing.T) {
+	app := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+	handler := NewQuotaHandler(app, QuotaLimits{RequestsPerMinute: 2})
+
+	for i := 0; i < 2; i++ {
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, withPrincipal(httptest.NewRequest(http.MethodGet, "/stream", nil), "bob"))
+		if rw.Code != http.StatusOK {
+			t.Fatalf("request %d: expected %d, got %d", i, http.StatusOK, rw.Code)
+		}
+	}
+
+	rw := httptest.NewRecorder()
+	handler.ServeHTTP(rw, withPrincipal(httptest.NewRequest(http.MethodGet, "/stream", nil), "bob"))
+	if rw.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected %d on the third request within a minute, got %d", http.StatusTooManyRequests, rw.Code)
+	}
+
+	retryAfter, err := strconv.Atoi(rw.Header().Get("Retry-After"))
+	if err != nil {
+		t.Fatalf("Retry-After is not an integer: %v", err)
+	}
+	if retryAfter <= 0 || retryAfter > 60 {
+		t.Fatalf("expected a Retry-After within the rolling minute, got %d", retryAfter)
+	}
+}
+
+func TestQuotaHandlerAllowsUnboundedTrafficWhenLimitsAreZero(t *testing.T) {
+	app := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {})
+	handler := NewQuotaHandler(app, QuotaLimits{})
+
+	for i := 0; i < 10; i++ {
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, withPrincipal(httptest.NewRequest(http.MethodGet, "/stream", nil), "carol"))
+		if rw.Code != http.StatusOK {
+			t.Fatalf("request %d: expected %d with no configured limits, got %d", i, http.StatusOK, rw.Code)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package firehose
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TokenStore resolves an opaque token (a bearer token, a basic-auth
+// password, or an "access_token" query parameter) into the Principal it
+// represents. Implementations return ok == false for unknown, revoked or
+// expired tokens.
+type TokenStore interface {
+	Lookup(token string) (principal *Principal, ok bool)
+}
+
+// tokenEntry pairs a Principal with its optional expiry, kept unexported
+// since TokenStore callers only ever need the resolved Principal.
+type tokenEntry struct {
+	principal Principal
+	expiry    time.Time
+}
+
+// MapTokenStore is a TokenStore backed by an in-memory map. It is safe for
+// concurrent use and is the default store for tests and small deployments.
+type MapTokenStore struct {
+	mu      sync.RWMutex
+	entries map[string]tokenEntry
+}
+
+// NewMapTokenStore returns an empty MapTokenStore.
+func NewMapTokenStore() *MapTokenStore {
+	return &MapTokenStore{entries: map[string]tokenEntry{}}
+}
+
+// Add registers a token with its Principal and an optional expiry. A zero
+// expiry means the token never expires.
+func (s *MapTokenStore) Add(token string, principal Principal, expiry time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[token] = tokenEntry{principal: principal, expiry: expiry}
+}
+
+// Lookup implements TokenStore.
+func (s *MapTokenStore) Lookup(token string) (*Principal, bool) {
+	s.mu.RLock()
+	entry, ok := s.entries[token]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, false
+	}
+	if !entry.expiry.IsZero() && time.Now().After(entry.expiry) {
+		return nil, false
+	}
+	principal := entry.principal
+	return &principal, true
+}
+
+// EnvTokenStore resolves a single token from an environment variable,
+// preserving the package's original single-shared-secret behaviour for
+// operators who don't need multiple tokens.
+type EnvTokenStore struct {
+	// Var is the name of the environment variable holding the token.
+	Var string
+	// Label is the Principal label returned on a match.
+	Label string
+}
+
+// Lookup implements TokenStore.
+func (s EnvTokenStore) Lookup(token string) (*Principal, bool) {
+	if token == "" || token != os.Getenv(s.Var) {
+		return nil, false
+	}
+	return &Principal{Label: s.Label}, true
+}
+
+// NewFileTokenStore reads "token label scope,scope,... expiry" lines
+// (whitespace separated) from path and returns a MapTokenStore populated
+// from them. Blank lines and lines starting with "#" are ignored. Scopes
+// are comma separated and optional ("-" or omitting the field means no
+// scopes). Expiry is an RFC 3339 timestamp; omitting it, or using "-",
+// means the token never expires.
+func NewFileTokenStore(path string) (*MapTokenStore, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	store := NewMapTokenStore()
+	scanner := bufio.NewScanner(f)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Fields(text)
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("%s:%d: expected at least \"token label\", got %q", path, line, text)
+		}
+
+		principal := Principal{Label: fields[1]}
+		if len(fields) > 2 && fields[2] != "-" {
+			principal.Scopes = strings.Split(fields[2], ",")
+		}
+
+		var expiry time.Time
+		if len(fields) > 3 && fields[3] != "-" {
+			expiry, err = time.Parse(time.RFC3339, fields[3])
+			if err != nil {
+				return nil, fmt.Errorf("%s:%d: invalid expiry %q: %w", path, line, fields[3], err)
+			}
+		}
+
+		store.Add(fields[0], principal, expiry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
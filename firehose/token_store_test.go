@@ -0,0 +1,143 @@
+package firehose
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestMapTokenStoreLookup(t *testing.T) {
+	store := NewMapTokenStore()
+	store.Add("tok1", Principal{Label: "alice"}, time.Time{})
+
+	principal, ok := store.Lookup("tok1")
+	if !ok {
+		t.Fatal("expected a match for a registered token")
+	}
+	if principal.Label != "alice" {
+		t.Fatalf("expected label %q, got %q", "alice", principal.Label)
+	}
+
+	if _, ok := store.Lookup("unknown"); ok {
+		t.Fatal("expected no match for an unregistered token")
+	}
+}
+
+func TestMapTokenStoreRejectsExpiredToken(t *testing.T) {
+	store := NewMapTokenStore()
+	store.Add("tok1", Principal{Label: "alice"}, time.Now().Add(-time.Minute))
+
+	if _, ok := store.Lookup("tok1"); ok {
+		t.Fatal("expected an expired token to be rejected")
+	}
+}
+
+func TestMapTokenStoreAllowsUnexpiredToken(t *testing.T) {
+	store := NewMapTokenStore()
+	store.Add("tok1", Principal{Label: "alice"}, time.Now().Add(time.Minute))
+
+	if _, ok := store.Lookup("tok1"); !ok {
+		t.Fatal("expected a token whose expiry is in the future to be accepted")
+	}
+}
+
+func TestEnvTokenStoreLookup(t *testing.T) {
+	const varName = "FIREHOSE_TEST_TOKEN"
+	os.Setenv(varName, "secret-token")
+	defer os.Unsetenv(varName)
+
+	store := EnvTokenStore{Var: varName, Label: "env-consumer"}
+
+	principal, ok := store.Lookup("secret-token")
+	if !ok {
+		t.Fatal("expected a match for the token held in the environment variable")
+	}
+	if principal.Label != "env-consumer" {
+		t.Fatalf("expected label %q, got %q", "env-consumer", principal.Label)
+	}
+
+	if _, ok := store.Lookup("wrong-token"); ok {
+		t.Fatal("expected a mismatched token to be rejected")
+	}
+	if _, ok := store.Lookup(""); ok {
+		t.Fatal("expected an empty token to be rejected even if the env var is also empty")
+	}
+}
+
+func writeTokenFile(t *testing.T, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "tokens")
+	if err := os.WriteFile(path, []byte(contents), 0600); err != nil {
+		t.Fatalf("failed to write token file: %v", err)
+	}
+	return path
+}
+
+func TestNewFileTokenStoreParsesScopesAndExpiry(t *testing.T) {
+	path := writeTokenFile(t, `
+# comment lines and blank lines are ignored
+
+tok1 alice read,write 2999-01-01T00:00:00Z
+tok2 bob - -
+`)
+
+	store, err := NewFileTokenStore(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	principal, ok := store.Lookup("tok1")
+	if !ok {
+		t.Fatal("expected tok1 to resolve")
+	}
+	if principal.Label != "alice" {
+		t.Fatalf("expected label %q, got %q", "alice", principal.Label)
+	}
+	if len(principal.Scopes) != 2 || principal.Scopes[0] != "read" || principal.Scopes[1] != "write" {
+		t.Fatalf("expected scopes [read write], got %v", principal.Scopes)
+	}
+
+	principal, ok = store.Lookup("tok2")
+	if !ok {
+		t.Fatal("expected tok2 to resolve")
+	}
+	if len(principal.Scopes) != 0 {
+		t.Fatalf("expected no scopes for a \"-\" field, got %v", principal.Scopes)
+	}
+}
+
+func TestNewFileTokenStoreRejectsExpiredToken(t *testing.T) {
+	path := writeTokenFile(t, "tok1 alice - 2000-01-01T00:00:00Z\n")
+
+	store, err := NewFileTokenStore(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, ok := store.Lookup("tok1"); ok {
+		t.Fatal("expected a token with a past expiry to be rejected")
+	}
+}
+
+func TestNewFileTokenStoreRejectsMalformedExpiry(t *testing.T) {
+	path := writeTokenFile(t, "tok1 alice - not-a-timestamp\n")
+
+	if _, err := NewFileTokenStore(path); err == nil {
+		t.Fatal("expected an error for a malformed expiry")
+	}
+}
+
+func TestNewFileTokenStoreRejectsShortLine(t *testing.T) {
+	path := writeTokenFile(t, "tok1\n")
+
+	if _, err := NewFileTokenStore(path); err == nil {
+		t.Fatal("expected an error for a line missing the label field")
+	}
+}
+
+func TestNewFileTokenStoreRejectsMissingFile(t *testing.T) {
+	if _, err := NewFileTokenStore(filepath.Join(t.TempDir(), "does-not-exist")); err == nil {
+		t.Fatal("expected an error for a missing file")
+	}
+}